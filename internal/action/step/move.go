@@ -12,6 +12,8 @@ import (
 	"github.com/hectorgimenez/d2go/pkg/data/state"
 	"github.com/hectorgimenez/koolo/internal/context"
 	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather"
+	"github.com/hectorgimenez/koolo/internal/pather/bfs"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
 )
@@ -20,12 +22,18 @@ const DistanceToFinishMoving = 4
 const stepMonsterCheckInterval = 100 * time.Millisecond
 
 var (
-	ErrMonstersInPath  = errors.New("monsters detected in movement path")
-	ErrPlayerStuck     = errors.New("player is stuck")
-	ErrPlayerRoundTrip = errors.New("player round trip")
-	ErrNoPath          = errors.New("path couldn't be calculated")
+	ErrMonstersInPath   = errors.New("monsters detected in movement path")
+	ErrPlayerStuck      = errors.New("player is stuck")
+	ErrPlayerRoundTrip  = errors.New("player round trip")
+	ErrNoPath           = errors.New("path couldn't be calculated")
+	ErrEscapedViaVertex = errors.New("escaped locked-in position via vertex planner")
+	ErrGuessExhausted   = errors.New("travel guess stopped making progress toward destination")
 )
 
+// maxVertexEscapeRadius is how far around the player we look for impassable
+// clusters (bodies, gore, chests, walls) to feed the vertex escape planner.
+const maxVertexEscapeRadius = 6
+
 type MoveOpts struct {
 	distanceOverride      *int
 	stationaryMinDistance *int
@@ -35,6 +43,46 @@ type MoveOpts struct {
 	ignoreItems           bool
 	monsterFilters        []data.MonsterFilter
 	clearPathOverride     *int
+	vertexEscape          bool
+	sharedState           *SharedMoveState
+	bfsManager            *bfs.Manager
+}
+
+// SharedMoveState lets a sequence of MoveTo calls share stuck and
+// round-trip detection state instead of each call starting with a clean
+// slate. step.Travel's TravelGuess recursion and step.Patrol's waypoint
+// legs both issue many consecutive MoveTo calls toward different
+// destinations; without a shared state a player who has been wobbling near
+// one spot for the last 8 seconds would get a fresh grace period on every
+// new leg.
+type SharedMoveState struct {
+	stuckCheckStartTime        time.Time
+	roundTripReferencePosition data.Position
+	roundTripCheckStartTime    time.Time
+	previousPosition           data.Position
+}
+
+// WithSharedMoveState makes MoveTo read and update its stuck/round-trip
+// timers from state instead of starting fresh.
+func WithSharedMoveState(state *SharedMoveState) MoveOption {
+	return func(opts *MoveOpts) {
+		opts.sharedState = state
+	}
+}
+
+// WithBFSMonsterCheck swaps the monster-in-path check's per-monster LOS+door
+// probe for a cheap reachability lookup against manager's cached BFS
+// distance grid, only falling back to a live DistanceFromMe measurement for
+// a monster the cache reports reachable - the cache can be reused while the
+// player has drifted (see bfs.MaxSourceDrift), so its cached Distance isn't
+// trustworthy as the live value, only as a reachable/unreachable filter.
+// Callers that issue many consecutive MoveTo calls (e.g. leveling/MF
+// supervisors) should keep one *bfs.Manager around and pass it here so the
+// BFS is computed once and reused across calls.
+func WithBFSMonsterCheck(manager *bfs.Manager) MoveOption {
+	return func(opts *MoveOpts) {
+		opts.bfsManager = manager
+	}
 }
 
 type MoveOption func(*MoveOpts)
@@ -84,6 +132,18 @@ func WithClearPathOverride(clearPathOverride int) MoveOption {
 	}
 }
 
+// WithVertexEscape enables the short-range vertex escape planner as a
+// fallback for when the long-range tile pathfinder reports ErrNoPath, or
+// keeps bouncing off the same obstacle (maxObstacleBypassAttempts
+// exhausted). This is meant for "locked-in" situations, e.g. a player
+// trapped behind a monster pack, a door frame, or an exploding-corpse
+// cluster that the long pathfinder marks impassable.
+func WithVertexEscape(enabled bool) MoveOption {
+	return func(opts *MoveOpts) {
+		opts.vertexEscape = enabled
+	}
+}
+
 func (opts MoveOpts) DistanceToFinish() *int {
 	return opts.distanceOverride
 }
@@ -127,13 +187,26 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 
 	blockThreshold := 200 * time.Millisecond
 	stuckThreshold := 2 * time.Second
-	stuckCheckStartTime := time.Now()
-
-	roundTripReferencePosition := ctx.Data.PlayerUnit.Position
-	roundTripCheckStartTime := time.Now()
 	const roundTripThreshold = 10 * time.Second
 	const roundTripMaxRadius = 8
 
+	// moveState carries the stuck/round-trip timers. Callers that issue many
+	// consecutive MoveTo legs (step.Travel's guess recursion, step.Patrol's
+	// waypoints) can pass WithSharedMoveState to keep this state across
+	// legs, so a player wobbling near one spot doesn't get a fresh grace
+	// period just because the destination changed.
+	moveState := opts.sharedState
+	if moveState == nil {
+		moveState = &SharedMoveState{}
+	}
+	if moveState.stuckCheckStartTime.IsZero() {
+		moveState.stuckCheckStartTime = time.Now()
+	}
+	if moveState.roundTripCheckStartTime.IsZero() {
+		moveState.roundTripReferencePosition = ctx.Data.PlayerUnit.Position
+		moveState.roundTripCheckStartTime = time.Now()
+	}
+
 	// Adaptive movement refresh intervals based on ping
 	// Adjust polling frequency based on network latency
 	var walkDuration time.Duration
@@ -150,7 +223,6 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 	}
 
 	lastRun := time.Time{}
-	previousPosition := data.Position{}
 	clearPathDist := ctx.CharacterCfg.Character.ClearPathDist
 	overrideClearPathDist := false
 	blocked := false
@@ -163,6 +235,11 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 
 	startArea := ctx.Data.PlayerUnit.Area
 
+	// retargetedDest overrides dest once NearestWalkable snaps it to a
+	// nearby walkable tile, e.g. when dest sits on a cell occupied by unit
+	// collision or a tile boundary under an NPC/object/portal.
+	var retargetedDest *data.Position
+
 	for {
 		ctx.PauseIfNotPriority()
 
@@ -194,6 +271,9 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 		}
 
 		currentDest := dest
+		if retargetedDest != nil {
+			currentDest = *retargetedDest
+		}
 
 		//Compute distance to destination
 		currentDistanceToDest := ctx.PathFinder.DistanceFromMe(currentDest)
@@ -218,10 +298,10 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 
 				// Update values before movement to maintain stuck detection
 				lastRun = time.Now()
-				if previousPosition != ctx.Data.PlayerUnit.Position {
+				if moveState.previousPosition != ctx.Data.PlayerUnit.Position {
 					obstacleBypassAttempts = 0 // Reset counter when player successfully moves
 				}
-				previousPosition = ctx.Data.PlayerUnit.Position
+				moveState.previousPosition = ctx.Data.PlayerUnit.Position
 
 				// Try to move past the obstacle
 				ctx.PathFinder.MoveThroughPath([]data.Position{movePastGridPos}, walkDuration)
@@ -230,6 +310,16 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 				// Continue to next iteration to re-evaluate position
 				continue
 			}
+		} else if opts.vertexEscape && ctx.Data.CanTeleport() && currentDistanceToDest <= minDistanceToFinishMoving && obstacleBypassAttempts >= maxObstacleBypassAttempts {
+			// Bouncing off the same obstacle too many times, try routing around it instead.
+			if escapePath, escaped := tryVertexEscape(ctx, currentDest); escaped {
+				ctx.Logger.Debug(ErrEscapedViaVertex.Error(),
+					slog.Int("corridorLength", len(escapePath)), slog.String("reason", "obstacle bypass attempts exhausted"))
+				obstacleBypassAttempts = 0
+				ctx.PathFinder.MoveThroughPath(escapePath, walkDuration)
+				utils.Sleep(100)
+				continue
+			}
 		}
 
 		//We've reached the destination, stop movement
@@ -285,19 +375,41 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 			stepLastMonsterCheck = time.Now()
 			monsterFound := false
 
-			for _, m := range ctx.Data.Monsters.Enemies(opts.monsterFilters...) {
-				if ctx.Char.ShouldIgnoreMonster(m) {
-					continue
+			if opts.bfsManager != nil {
+				grid := collisionGridAdapter{grid: ctx.Data.AreaData.Grid.CollisionGrid, origin: ctx.Data.AreaOrigin}
+				cache := opts.bfsManager.Get(grid, ctx.Data.AreaOrigin, ctx.Data.PlayerUnit.Area, ctx.Data.PlayerUnit.Position)
+
+				for _, m := range ctx.Data.Monsters.Enemies(opts.monsterFilters...) {
+					if ctx.Char.ShouldIgnoreMonster(m) {
+						continue
+					}
+					//The cache can be reused while the player has drifted up
+					//to bfs.MaxSourceDrift tiles from where it was rooted, so
+					//its cached Distance is only trustworthy as a
+					//reachability filter (is m connected to the player's
+					//neighborhood at all), not as the live distance - that
+					//still has to be measured fresh from the player's actual
+					//current position before comparing against clearPathDist.
+					if _, reachable := cache.ClosestFoe(data.Monsters{m}); reachable && ctx.PathFinder.DistanceFromMe(m.Position) <= clearPathDist {
+						monsterFound = true
+						break
+					}
 				}
-				//Check distance first as it is cheaper
-				distanceToMonster := ctx.PathFinder.DistanceFromMe(m.Position)
-				if distanceToMonster <= clearPathDist {
-					//Line of sight second
-					if ctx.PathFinder.LineOfSight(ctx.Data.PlayerUnit.Position, m.Position) {
-						//Finally door check as it computes path
-						if hasDoorBetween, _ := ctx.PathFinder.HasDoorBetween(ctx.Data.PlayerUnit.Position, m.Position); !hasDoorBetween {
-							monsterFound = true
-							break
+			} else {
+				for _, m := range ctx.Data.Monsters.Enemies(opts.monsterFilters...) {
+					if ctx.Char.ShouldIgnoreMonster(m) {
+						continue
+					}
+					//Check distance first as it is cheaper
+					distanceToMonster := ctx.PathFinder.DistanceFromMe(m.Position)
+					if distanceToMonster <= clearPathDist {
+						//Line of sight second
+						if ctx.PathFinder.LineOfSight(ctx.Data.PlayerUnit.Position, m.Position) {
+							//Finally door check as it computes path
+							if hasDoorBetween, _ := ctx.PathFinder.HasDoorBetween(ctx.Data.PlayerUnit.Position, m.Position); !hasDoorBetween {
+								monsterFound = true
+								break
+							}
 						}
 					}
 				}
@@ -311,8 +423,8 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 		currentPosition := ctx.Data.PlayerUnit.Position
 		blocked = false
 		//Detect if player is doing round trips around a position for too long and return error if it's the case
-		if utils.CalculateDistance(currentPosition, roundTripReferencePosition) <= roundTripMaxRadius {
-			timeInRoundtrip := time.Since(roundTripCheckStartTime)
+		if utils.CalculateDistance(currentPosition, moveState.roundTripReferencePosition) <= roundTripMaxRadius {
+			timeInRoundtrip := time.Since(moveState.roundTripCheckStartTime)
 			if timeInRoundtrip > roundTripThreshold {
 				ctx.Logger.Warn("Player is doing round trips. Current area: [" + ctx.Data.PlayerUnit.Area.Area().Name + "]. Trying to path to Destination: [" + fmt.Sprintf("%d,%d", currentDest.X, currentDest.Y) + "]")
 				return ErrPlayerRoundTrip
@@ -321,12 +433,12 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 			}
 		} else {
 			//Player moved significantly, reset Round Trip detection
-			roundTripReferencePosition = currentPosition
-			roundTripCheckStartTime = time.Now()
+			moveState.roundTripReferencePosition = currentPosition
+			moveState.roundTripCheckStartTime = time.Now()
 		}
 
-		if currentPosition == previousPosition && !ctx.Data.PlayerUnit.States.HasState(state.Stunned) {
-			stuckTime := time.Since(stuckCheckStartTime)
+		if currentPosition == moveState.previousPosition && !ctx.Data.PlayerUnit.States.HasState(state.Stunned) {
+			stuckTime := time.Since(moveState.stuckCheckStartTime)
 			if stuckTime > stuckThreshold {
 				//if stuck for too long, abort movement
 				return ErrPlayerStuck
@@ -336,7 +448,7 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 			}
 		} else {
 			//Player moved, reset stuck detection timer
-			stuckCheckStartTime = time.Now()
+			moveState.stuckCheckStartTime = time.Now()
 		}
 
 		if blocked {
@@ -387,6 +499,28 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 		//Compute path to reach destination
 		path, _, found := ctx.PathFinder.GetPath(currentDest)
 		if !found {
+			if retargetedDest == nil {
+				grid := collisionGridAdapter{grid: ctx.Data.AreaData.Grid.CollisionGrid, origin: ctx.Data.AreaOrigin}
+				hasPath := func(pos data.Position) bool {
+					_, _, found := ctx.PathFinder.GetPath(pos)
+					return found
+				}
+				if snapped, snappedFound := pather.NearestWalkable(grid, currentDest, pather.DefaultNearestWalkableRadius, hasPath); snappedFound {
+					ctx.Logger.Debug("Destination isn't walkable, re-targeting to nearest walkable tile",
+						slog.Any("original", currentDest), slog.Any("snapped", snapped))
+					retargetedDest = &snapped
+					continue
+				}
+			}
+			if opts.vertexEscape {
+				if escapePath, escaped := tryVertexEscape(ctx, currentDest); escaped {
+					ctx.Logger.Debug(ErrEscapedViaVertex.Error(),
+						slog.Int("corridorLength", len(escapePath)))
+					ctx.PathFinder.MoveThroughPath(escapePath, walkDuration)
+					utils.Sleep(100)
+					continue
+				}
+			}
 			//Couldn't find path, abort movement
 			ctx.Logger.Warn("path could not be calculated. Current area: [" + ctx.Data.PlayerUnit.Area.Area().Name + "]. Trying to path to Destination: [" + fmt.Sprintf("%d,%d", currentDest.X, currentDest.Y) + "]")
 			return ErrNoPath
@@ -475,10 +609,10 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 
 				// Update values before movement to maintain stuck detection
 				lastRun = time.Now()
-				if previousPosition != ctx.Data.PlayerUnit.Position {
+				if moveState.previousPosition != ctx.Data.PlayerUnit.Position {
 					obstacleBypassAttempts = 0 // Reset counter when player successfully moves
 				}
-				previousPosition = ctx.Data.PlayerUnit.Position
+				moveState.previousPosition = ctx.Data.PlayerUnit.Position
 
 				ctx.PathFinder.MoveThroughPath([]data.Position{movePastGridPos}, walkDuration)
 				utils.Sleep(100)
@@ -493,12 +627,81 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 
 		//Update values
 		lastRun = time.Now()
-		if previousPosition != ctx.Data.PlayerUnit.Position {
+		if moveState.previousPosition != ctx.Data.PlayerUnit.Position {
 			obstacleBypassAttempts = 0 // Reset counter when player successfully moves
 		}
-		previousPosition = ctx.Data.PlayerUnit.Position
+		moveState.previousPosition = ctx.Data.PlayerUnit.Position
 
 		//Perform the movement
 		ctx.PathFinder.MoveThroughPath(path, walkDuration)
 	}
 }
+
+// collisionGridAdapter exposes the area's collision grid through
+// pather.EscapeGrid, translating between world coordinates and the
+// grid-relative coordinates the collision grid is indexed by.
+type collisionGridAdapter struct {
+	grid   [][]bool
+	origin data.Position
+}
+
+func (a collisionGridAdapter) IsWalkable(x, y int) bool {
+	gx, gy := x-a.origin.X, y-a.origin.Y
+	if gy < 0 || gy >= len(a.grid) || gx < 0 || gx >= len(a.grid[gy]) {
+		return false
+	}
+	return a.grid[gy][gx]
+}
+
+func (a collisionGridAdapter) Width() int {
+	if len(a.grid) == 0 {
+		return 0
+	}
+	return len(a.grid[0])
+}
+
+func (a collisionGridAdapter) Height() int {
+	return len(a.grid)
+}
+
+// tryVertexEscape gathers the impassable clusters immediately around the
+// player and hands them to pather.FindVertexEscape, looking for a short
+// corridor (up to pather.MaxVertexEscapeTiles) that either reaches dest
+// directly or lands on a tile from which dest becomes reachable again.
+func tryVertexEscape(ctx *context.Status, dest data.Position) ([]data.Position, bool) {
+	grid := collisionGridAdapter{grid: ctx.Data.AreaData.Grid.CollisionGrid, origin: ctx.Data.AreaOrigin}
+
+	var obstacles []pather.ObstaclePolygon
+	playerPos := ctx.Data.PlayerUnit.Position
+	for dy := -maxVertexEscapeRadius; dy <= maxVertexEscapeRadius; dy++ {
+		for dx := -maxVertexEscapeRadius; dx <= maxVertexEscapeRadius; dx++ {
+			candidate := data.Position{X: playerPos.X + dx, Y: playerPos.Y + dy}
+			if !grid.IsWalkable(candidate.X, candidate.Y) {
+				obstacles = append(obstacles, pather.NewBoxObstacle(candidate, 1))
+			}
+		}
+	}
+
+	// ctx.PathFinder.GetPath is always rooted at the player's actual (still
+	// stuck) position, so it can't tell us whether dest becomes reachable
+	// from a candidate vertex the player hasn't moved to yet - it would just
+	// report the nearby, already-visible candidate itself as "reachable".
+	// LineOfSight takes two arbitrary points (as findGuessTarget also relies
+	// on), so use it to test reachability from the candidate to dest.
+	isLongRangeValid := func(pos data.Position) bool {
+		return pos == dest || ctx.PathFinder.LineOfSight(pos, dest)
+	}
+
+	escapePath, found := pather.FindVertexEscape(grid, playerPos, dest, obstacles, isLongRangeValid)
+	if !found {
+		return nil, false
+	}
+
+	areaOrigin := ctx.Data.AreaOrigin
+	gridPath := make([]data.Position, len(escapePath))
+	for i, p := range escapePath {
+		gridPath[i] = data.Position{X: p.X - areaOrigin.X, Y: p.Y - areaOrigin.Y}
+	}
+
+	return gridPath, true
+}
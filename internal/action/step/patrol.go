@@ -0,0 +1,194 @@
+package step
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/context"
+)
+
+// PatrolOpts configures step.Patrol.
+type PatrolOpts struct {
+	pingPong      bool
+	loop          bool
+	dwell         time.Duration
+	monsterEngage int
+	engageFunc    func(data.Monster) error
+}
+
+type PatrolOption func(*PatrolOpts)
+
+// WithPingPong makes the patrol reverse direction at each end of the
+// waypoint list instead of wrapping back to the first waypoint.
+func WithPingPong() PatrolOption {
+	return func(opts *PatrolOpts) {
+		opts.pingPong = true
+	}
+}
+
+// WithLoop makes the patrol repeat indefinitely instead of stopping after a
+// single pass over the waypoints.
+func WithLoop() PatrolOption {
+	return func(opts *PatrolOpts) {
+		opts.loop = true
+	}
+}
+
+// WithDwell pauses for the given duration at each waypoint before moving to
+// the next one, e.g. for a guard rotation that should linger.
+func WithDwell(perWaypoint time.Duration) PatrolOption {
+	return func(opts *PatrolOpts) {
+		opts.dwell = perWaypoint
+	}
+}
+
+// WithMonsterEngage breaks the patrol to run engage against any monster
+// within rng tiles of the player, then resumes at the next waypoint once
+// engage returns. step doesn't own combat logic, so the caller supplies it
+// (e.g. the leveling or MF supervisor's own kill-monster routine).
+func WithMonsterEngage(rng int, engage func(data.Monster) error) PatrolOption {
+	return func(opts *PatrolOpts) {
+		opts.monsterEngage = rng
+		opts.engageFunc = engage
+	}
+}
+
+// doorCloseDelay is how long a door stays in Patrol's "recently opened"
+// set before the entry expires. The set is only recorded and expired; it
+// is not passed to MoveTo, which opens any closed door on its path as usual.
+const doorCloseDelay = 5 * time.Second
+
+// Patrol walks waypoints in sequence, handing off each leg to MoveTo while
+// preserving state between legs: a shared stuck detector, a shared
+// round-trip reference position (see SharedMoveState), and a per-door
+// "recently opened" set (see doorCloseDelay; MoveTo does not read it). This
+// gives supervisors (leveling, MF runs with guard rotations, mercenary
+// escort tasks) a first-class primitive instead of hand-rolling a for loop
+// around MoveTo.
+func Patrol(waypoints []data.Position, opts ...PatrolOption) error {
+	patrolOpts := &PatrolOpts{}
+	for _, o := range opts {
+		o(patrolOpts)
+	}
+
+	if len(waypoints) == 0 {
+		return nil
+	}
+
+	sharedState := &SharedMoveState{}
+	moveOpts := []MoveOption{WithSharedMoveState(sharedState)}
+
+	recentlyOpenedDoors := map[data.UnitID]time.Time{}
+
+	index := 0
+	direction := 1
+
+	for {
+		if patrolOpts.monsterEngage > 0 && patrolOpts.engageFunc != nil {
+			if err := engageNearbyMonsters(patrolOpts.monsterEngage, patrolOpts.engageFunc); err != nil {
+				return err
+			}
+		}
+
+		if err := moveToPatrolWaypoint(waypoints[index], recentlyOpenedDoors, moveOpts...); err != nil {
+			// MoveTo's own monster check (unrelated to WithMonsterEngage's
+			// rng) can abort the leg on its own. If we have an engage
+			// function, don't let that end the whole patrol - loop back
+			// around to the engage branch above and retry this waypoint.
+			if errors.Is(err, ErrMonstersInPath) && patrolOpts.engageFunc != nil {
+				continue
+			}
+			return err
+		}
+
+		if patrolOpts.dwell > 0 {
+			time.Sleep(patrolOpts.dwell)
+		}
+
+		nextIndex, nextDirection, done := advancePatrol(index, direction, len(waypoints), patrolOpts)
+		if done {
+			return nil
+		}
+		index, direction = nextIndex, nextDirection
+	}
+}
+
+// advancePatrol computes the next waypoint index for a ring, ping-pong, or
+// single-pass patrol.
+func advancePatrol(index, direction, total int, opts *PatrolOpts) (nextIndex, nextDirection int, done bool) {
+	if total == 1 {
+		if !opts.loop {
+			return index, direction, true
+		}
+		return index, direction, false
+	}
+
+	next := index + direction
+
+	if opts.pingPong {
+		// Detect "we just bounced off index 0 heading backward" using the
+		// pre-flip direction - once direction is overwritten below there's
+		// no way to tell we completed the round trip back to the start.
+		completedRoundTrip := next < 0 && index == 0 && direction == -1
+
+		if next < 0 || next >= total {
+			direction = -direction
+			next = index + direction
+		}
+		if !opts.loop && completedRoundTrip {
+			return index, direction, true
+		}
+		return next, direction, false
+	}
+
+	// Ring: wrap around to the start.
+	if next >= total {
+		if !opts.loop {
+			return index, direction, true
+		}
+		next = 0
+	}
+
+	return next, direction, false
+}
+
+// moveToPatrolWaypoint moves to dest via MoveTo, first expiring entries
+// older than doorCloseDelay and recording the door (if any) between the
+// player and dest in recentlyOpenedDoors. MoveTo opens doors on its own and
+// does not consult recentlyOpenedDoors, so door handling is unchanged by it.
+func moveToPatrolWaypoint(dest data.Position, recentlyOpenedDoors map[data.UnitID]time.Time, moveOpts ...MoveOption) error {
+	ctx := context.Get()
+
+	for id, openedAt := range recentlyOpenedDoors {
+		if time.Since(openedAt) > doorCloseDelay {
+			delete(recentlyOpenedDoors, id)
+		}
+	}
+
+	if doorFound, doorObj := ctx.PathFinder.HasDoorBetween(ctx.Data.PlayerUnit.Position, dest); doorFound {
+		if _, recentlyOpened := recentlyOpenedDoors[doorObj.ID]; !recentlyOpened {
+			recentlyOpenedDoors[doorObj.ID] = time.Now()
+		}
+	}
+
+	return MoveTo(dest, moveOpts...)
+}
+
+// engageNearbyMonsters pauses the patrol to run engage against the first
+// monster found within rng tiles of the player before resuming.
+func engageNearbyMonsters(rng int, engage func(data.Monster) error) error {
+	ctx := context.Get()
+	ctx.RefreshGameData()
+
+	for _, m := range ctx.Data.Monsters.Enemies() {
+		if ctx.Char.ShouldIgnoreMonster(m) {
+			continue
+		}
+		if ctx.PathFinder.DistanceFromMe(m.Position) <= rng {
+			return engage(m)
+		}
+	}
+
+	return nil
+}
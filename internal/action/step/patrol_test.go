@@ -0,0 +1,100 @@
+package step
+
+import "testing"
+
+func TestAdvancePatrol_PingPongNoLoopTerminates(t *testing.T) {
+	opts := &PatrolOpts{pingPong: true}
+	total := 3
+
+	type step struct {
+		wantIndex int
+		wantDone  bool
+	}
+	// Waypoints 0,1,2: expect 0 -> 1 -> 2 -> 1 -> 0 and then stop.
+	want := []step{
+		{1, false},
+		{2, false},
+		{1, false},
+		{0, false},
+		{0, true}, // round trip complete, advancePatrol signals done on the next call
+	}
+
+	index, direction := 0, 1
+	for i, w := range want {
+		nextIndex, nextDirection, done := advancePatrol(index, direction, total, opts)
+		if done != w.wantDone {
+			t.Fatalf("step %d: done = %v, want %v", i, done, w.wantDone)
+		}
+		if !done && nextIndex != w.wantIndex {
+			t.Fatalf("step %d: index = %d, want %d", i, nextIndex, w.wantIndex)
+		}
+		if done {
+			break
+		}
+		index, direction = nextIndex, nextDirection
+	}
+}
+
+func TestAdvancePatrol_PingPongLoopNeverTerminates(t *testing.T) {
+	opts := &PatrolOpts{pingPong: true, loop: true}
+	total := 3
+
+	index, direction := 0, 1
+	for i := 0; i < 20; i++ {
+		nextIndex, nextDirection, done := advancePatrol(index, direction, total, opts)
+		if done {
+			t.Fatalf("iteration %d: looping ping-pong patrol should never report done", i)
+		}
+		index, direction = nextIndex, nextDirection
+	}
+}
+
+func TestAdvancePatrol_RingStopsAfterOnePassWithoutLoop(t *testing.T) {
+	opts := &PatrolOpts{}
+	total := 3
+
+	index, direction := 0, 1
+	for _, want := range []int{1, 2} {
+		nextIndex, _, done := advancePatrol(index, direction, total, opts)
+		if done {
+			t.Fatalf("unexpected done before completing the ring")
+		}
+		if nextIndex != want {
+			t.Fatalf("index = %d, want %d", nextIndex, want)
+		}
+		index = nextIndex
+	}
+
+	_, _, done := advancePatrol(index, direction, total, opts)
+	if !done {
+		t.Error("ring patrol without WithLoop should stop after one full pass")
+	}
+}
+
+func TestAdvancePatrol_RingLoopsWithLoop(t *testing.T) {
+	opts := &PatrolOpts{loop: true}
+	total := 3
+
+	index, direction := 2, 1
+	nextIndex, _, done := advancePatrol(index, direction, total, opts)
+	if done {
+		t.Fatal("looping ring patrol should never report done")
+	}
+	if nextIndex != 0 {
+		t.Errorf("index = %d, want wraparound to 0", nextIndex)
+	}
+}
+
+func TestAdvancePatrol_SingleWaypoint(t *testing.T) {
+	opts := &PatrolOpts{}
+	_, _, done := advancePatrol(0, 1, 1, opts)
+	if !done {
+		t.Error("a single waypoint without WithLoop should be done after visiting it once")
+	}
+
+	opts = &PatrolOpts{loop: true}
+	_, _, done = advancePatrol(0, 1, 1, opts)
+	if done {
+		t.Error("a single waypoint with WithLoop should never be done")
+	}
+}
@@ -0,0 +1,149 @@
+package step
+
+import (
+	"errors"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/pather"
+	"github.com/hectorgimenez/koolo/internal/utils"
+)
+
+// TravelMode selects how step.Travel behaves when the destination isn't
+// reachable by the long-range pathfinder.
+type TravelMode int
+
+const (
+	// TravelExact behaves exactly like MoveTo (including MoveTo's snap to a
+	// nearby walkable tile) and never falls back to a guess target.
+	TravelExact TravelMode = iota
+	// TravelGuess falls back to the closest reachable tile that either
+	// sees dest via line of sight or is the nearest known tile to dest by
+	// Euclidean distance, then recurses from there to try dest again. This
+	// is useful for approaching objectives in fog-of-war areas (Maggot
+	// Lair, Arcane Sanctuary) where the exact destination tile hasn't been
+	// revealed yet.
+	TravelGuess
+	// TravelValid performs no movement. It returns nil when dest, or
+	// failing that a TravelGuess target near it (see findGuessTarget), is
+	// currently reachable, and ErrNoPath otherwise, so task planners can
+	// pre-flight check a destination before committing to it.
+	TravelValid
+)
+
+const maxTravelGuessIterations = 8
+const travelGuessSearchRadius = 30
+
+// Travel moves to dest according to mode, sharing MoveTo's stuck/round-trip
+// detectors across every leg it issues (see SharedMoveState) so a long
+// TravelGuess chase doesn't get a fresh grace period on every recursion.
+func Travel(dest data.Position, mode TravelMode, options ...MoveOption) error {
+	switch mode {
+	case TravelExact:
+		return MoveTo(dest, options...)
+	case TravelValid:
+		return travelValid(dest, options...)
+	case TravelGuess:
+		return travelGuess(dest, options...)
+	default:
+		return MoveTo(dest, options...)
+	}
+}
+
+func travelValid(dest data.Position, options ...MoveOption) error {
+	ctx := context.Get()
+	ctx.RefreshGameData()
+
+	if _, _, found := ctx.PathFinder.GetPath(dest); found {
+		return nil
+	}
+
+	guessDest, found := findGuessTarget(ctx, dest)
+	if !found {
+		return ErrNoPath
+	}
+	if _, _, found := ctx.PathFinder.GetPath(guessDest); !found {
+		return ErrNoPath
+	}
+
+	return nil
+}
+
+func travelGuess(dest data.Position, options ...MoveOption) error {
+	ctx := context.Get()
+	sharedState := &SharedMoveState{}
+	opts := append(append([]MoveOption{}, options...), WithSharedMoveState(sharedState))
+
+	bestRemaining := -1
+
+	for i := 0; i < maxTravelGuessIterations; i++ {
+		err := MoveTo(dest, opts...)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrNoPath) {
+			return err
+		}
+
+		guessDest, found := findGuessTarget(ctx, dest)
+		if !found {
+			return ErrGuessExhausted
+		}
+
+		remaining := int(utils.CalculateDistance(guessDest, dest))
+		if bestRemaining >= 0 && remaining >= bestRemaining {
+			return ErrGuessExhausted
+		}
+		bestRemaining = remaining
+
+		if err := MoveTo(guessDest, opts...); err != nil {
+			return err
+		}
+	}
+
+	return ErrGuessExhausted
+}
+
+// findGuessTarget spirals the collision grid around dest ring by ring (see
+// pather.RingTiles) looking for the closest walkable, long-range-reachable
+// tile that either sees dest via line of sight, or - failing that - is
+// simply the nearest such tile to dest by Euclidean distance. It stops at
+// the first ring containing an acceptable candidate instead of pathing
+// every tile in the whole search box, since GetPath is the expensive part
+// and a nearer ring is always preferable to a farther one anyway.
+func findGuessTarget(ctx *context.Status, dest data.Position) (data.Position, bool) {
+	grid := collisionGridAdapter{grid: ctx.Data.AreaData.Grid.CollisionGrid, origin: ctx.Data.AreaOrigin}
+	hasPath := func(pos data.Position) bool {
+		_, _, found := ctx.PathFinder.GetPath(pos)
+		return found
+	}
+
+	if grid.IsWalkable(dest.X, dest.Y) && hasPath(dest) {
+		return dest, true
+	}
+
+	for radius := 1; radius <= travelGuessSearchRadius; radius++ {
+		var bestPos data.Position
+		var bestDist float64
+		bestHasLOS := false
+		found := false
+
+		for _, candidate := range pather.RingTiles(dest, radius) {
+			if !grid.IsWalkable(candidate.X, candidate.Y) || !hasPath(candidate) {
+				continue
+			}
+
+			hasLOS := ctx.PathFinder.LineOfSight(candidate, dest)
+			dist := utils.CalculateDistance(candidate, dest)
+			if !found || (hasLOS && !bestHasLOS) || (hasLOS == bestHasLOS && dist < bestDist) {
+				bestPos, bestDist, bestHasLOS, found = candidate, dist, hasLOS, true
+			}
+		}
+
+		if found {
+			return bestPos, true
+		}
+	}
+
+	return data.Position{}, false
+}
@@ -0,0 +1,369 @@
+// Package bfs provides a per-area cached breadth-first search over the
+// collision grid, reused across many "closest-X" queries instead of running
+// a fresh Dijkstra/BFS for every `DistanceFromMe` style check. The pattern
+// mirrors the cached-BFS approach common in roguelike AI: compute one
+// distance/parent grid from the player's position, invalidate it when the
+// area, the player's reference position, or the collision grid itself
+// changes significantly, and serve queries off of it in O(k) where k is the
+// number of candidates being compared.
+package bfs
+
+import (
+	"math"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/object"
+	"github.com/hectorgimenez/koolo/internal/pather"
+	"github.com/hectorgimenez/koolo/internal/utils"
+)
+
+// Unreachable marks a tile that the cached BFS never visited.
+const Unreachable uint16 = math.MaxUint16
+
+// MaxCollisionDelta is how many collision-grid tiles are allowed to change
+// before a cache is considered stale and must be recomputed, even if the
+// area and source position haven't changed (e.g. a corpse pile forms or a
+// door closes mid-run).
+const MaxCollisionDelta = 25
+
+// MaxSourceDrift is how far (in tiles) the source position can drift from
+// the one the cache was computed for before it's considered stale. A BFS
+// distance grid stays a good approximation for queries near where it was
+// rooted, so normal walking within this radius reuses the cache instead of
+// forcing a full-grid recompute on every tick - only area change, teleport,
+// or exceeding this drift (or MaxCollisionDelta) triggers a rebuild.
+const MaxSourceDrift = 10
+
+// ItemFilter decides whether a single item is a valid ClosestItem candidate.
+type ItemFilter func(data.Item) bool
+
+// Result is returned by every closest-X query: the matched position, its
+// distance in tiles from the cache's source position, and the
+// grid-relative path to reach it, reconstructed from the parent grid.
+type Result struct {
+	Position data.Position
+	Distance uint16
+	Path     []data.Position
+}
+
+// key identifies a single cached BFS run.
+type key struct {
+	area   area.ID
+	source data.Position
+}
+
+// Cache holds a single area's distance grid and parent-pointer grid,
+// computed once from a source position via bucketed BFS.
+type Cache struct {
+	key           key
+	origin        data.Position
+	width, height int
+	dist          []uint16
+	parent        []int32 // index into dist/parent, -1 for the source tile
+}
+
+// Manager owns the single active Cache and decides when it must be
+// recomputed. It is safe to keep one Manager per bot instance and call Get
+// on every tick; Get is a no-op rebuild when nothing relevant changed.
+type Manager struct {
+	current       *Cache
+	changedTiles  int
+	teleportDirty bool
+}
+
+// NewManager returns an empty Manager with no cached BFS yet.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// InvalidateArea forces a recompute on the next Get, used when the player
+// changes area.
+func (m *Manager) InvalidateArea() {
+	m.current = nil
+	m.changedTiles = 0
+	m.teleportDirty = false
+}
+
+// InvalidateTeleport forces a recompute on the next Get, used after a
+// teleport since the source position jumps discontinuously.
+func (m *Manager) InvalidateTeleport() {
+	m.teleportDirty = true
+}
+
+// NoteCollisionChange accumulates collision-grid changes (objects
+// destroyed, doors opened, corpses piling up) observed since the last
+// compute. Once MaxCollisionDelta is exceeded the cache is rebuilt on the
+// next Get even if the source position hasn't moved.
+func (m *Manager) NoteCollisionChange(tiles int) {
+	m.changedTiles += tiles
+}
+
+// Get returns a Cache for areaID/source, computing it if the Manager
+// doesn't already hold a fresh one.
+func (m *Manager) Get(grid pather.EscapeGrid, origin data.Position, areaID area.ID, source data.Position) *Cache {
+	if m.current != nil &&
+		!m.teleportDirty &&
+		m.current.key.area == areaID &&
+		m.changedTiles <= MaxCollisionDelta &&
+		utils.CalculateDistance(m.current.key.source, source) <= MaxSourceDrift {
+		return m.current
+	}
+
+	m.current = compute(grid, origin, areaID, source)
+	m.changedTiles = 0
+	m.teleportDirty = false
+
+	return m.current
+}
+
+// compute runs a bucketed BFS (a plain FIFO queue, since every tile
+// transition costs 1) from source over grid, filling in dist and parent for
+// every reachable tile.
+func compute(grid pather.EscapeGrid, origin data.Position, areaID area.ID, source data.Position) *Cache {
+	width, height := grid.Width(), grid.Height()
+	c := &Cache{
+		key:    key{area: areaID, source: source},
+		origin: origin,
+		width:  width,
+		height: height,
+		dist:   make([]uint16, width*height),
+		parent: make([]int32, width*height),
+	}
+
+	for i := range c.dist {
+		c.dist[i] = Unreachable
+		c.parent[i] = -1
+	}
+
+	sx, sy := source.X-origin.X, source.Y-origin.Y
+	if sx < 0 || sx >= width || sy < 0 || sy >= height {
+		return c
+	}
+
+	startIdx := sy*width + sx
+	c.dist[startIdx] = 0
+
+	queue := make([]int, 0, width*height/4)
+	queue = append(queue, startIdx)
+
+	for head := 0; head < len(queue); head++ {
+		idx := queue[head]
+		x, y := idx%width, idx/width
+		d := c.dist[idx]
+
+		for _, n := range neighbors(x, y, width, height) {
+			nIdx := n.y*width + n.x
+			if !grid.IsWalkable(n.x+origin.X, n.y+origin.Y) {
+				continue
+			}
+			if c.dist[nIdx] != Unreachable {
+				continue
+			}
+			c.dist[nIdx] = d + 1
+			c.parent[nIdx] = int32(idx)
+			queue = append(queue, nIdx)
+		}
+	}
+
+	return c
+}
+
+type tile struct{ x, y int }
+
+func neighbors(x, y, width, height int) []tile {
+	candidates := [8]tile{
+		{x + 1, y}, {x - 1, y}, {x, y + 1}, {x, y - 1},
+		{x + 1, y + 1}, {x + 1, y - 1}, {x - 1, y + 1}, {x - 1, y - 1},
+	}
+
+	out := make([]tile, 0, 8)
+	for _, t := range candidates {
+		if t.x >= 0 && t.x < width && t.y >= 0 && t.y < height {
+			out = append(out, t)
+		}
+	}
+
+	return out
+}
+
+// distanceAt returns the cached distance for a world position, or
+// Unreachable if it's outside the grid or never visited.
+func (c *Cache) distanceAt(pos data.Position) uint16 {
+	x, y := pos.X-c.origin.X, pos.Y-c.origin.Y
+	if x < 0 || x >= c.width || y < 0 || y >= c.height {
+		return Unreachable
+	}
+	return c.dist[y*c.width+x]
+}
+
+// pathTo reconstructs the grid-relative path from the cache's source tile
+// to pos by walking parent pointers backwards.
+func (c *Cache) pathTo(pos data.Position) []data.Position {
+	x, y := pos.X-c.origin.X, pos.Y-c.origin.Y
+	idx := y*c.width + x
+
+	var indices []int
+	for idx != -1 {
+		indices = append(indices, idx)
+		idx = int(c.parent[idx])
+	}
+
+	path := make([]data.Position, len(indices))
+	for i, pidx := range indices {
+		path[len(indices)-1-i] = data.Position{X: pidx % c.width, Y: pidx / c.width}
+	}
+
+	return path
+}
+
+func (c *Cache) toResult(pos data.Position) Result {
+	return Result{
+		Position: pos,
+		Distance: c.distanceAt(pos),
+		Path:     c.pathTo(pos),
+	}
+}
+
+// ClosestFoe returns the reachable monster in monsters (already reduced by
+// filters) with the lowest cached distance.
+func (c *Cache) ClosestFoe(monsters data.Monsters, filters ...data.MonsterFilter) (Result, bool) {
+	for _, f := range filters {
+		monsters = f(monsters)
+	}
+
+	best := Unreachable
+	var bestPos data.Position
+	found := false
+
+	for _, m := range monsters {
+		d := c.distanceAt(m.Position)
+		if d == Unreachable {
+			continue
+		}
+		if !found || d < best {
+			best, bestPos, found = d, m.Position, true
+		}
+	}
+
+	if !found {
+		return Result{}, false
+	}
+
+	return c.toResult(bestPos), true
+}
+
+// ClosestItem returns the reachable item matching filter with the lowest
+// cached distance.
+func (c *Cache) ClosestItem(items data.Items, filter ItemFilter) (Result, bool) {
+	best := Unreachable
+	var bestPos data.Position
+	found := false
+
+	for _, it := range items {
+		if filter != nil && !filter(it) {
+			continue
+		}
+		d := c.distanceAt(it.Position)
+		if d == Unreachable {
+			continue
+		}
+		if !found || d < best {
+			best, bestPos, found = d, it.Position, true
+		}
+	}
+
+	if !found {
+		return Result{}, false
+	}
+
+	return c.toResult(bestPos), true
+}
+
+// ClosestTrigger returns the reachable object of one of objectTypes (a
+// door, shrine, waypoint, ...) with the lowest cached distance.
+func (c *Cache) ClosestTrigger(objects data.Objects, objectTypes ...object.Type) (Result, bool) {
+	wanted := make(map[object.Type]bool, len(objectTypes))
+	for _, t := range objectTypes {
+		wanted[t] = true
+	}
+
+	best := Unreachable
+	var bestPos data.Position
+	found := false
+
+	for _, o := range objects {
+		if len(wanted) > 0 && !wanted[o.Type] {
+			continue
+		}
+		d := c.distanceAt(o.Position)
+		if d == Unreachable {
+			continue
+		}
+		if !found || d < best {
+			best, bestPos, found = d, o.Position, true
+		}
+	}
+
+	if !found {
+		return Result{}, false
+	}
+
+	return c.toResult(bestPos), true
+}
+
+// ClosestUnknown returns the nearest reachable tile that isRevealed reports
+// as not yet revealed, useful for exploration-driven supervisors.
+func (c *Cache) ClosestUnknown(isRevealed func(data.Position) bool) (Result, bool) {
+	best := Unreachable
+	var bestPos data.Position
+	found := false
+
+	for y := 0; y < c.height; y++ {
+		for x := 0; x < c.width; x++ {
+			d := c.dist[y*c.width+x]
+			if d == Unreachable || (found && d >= best) {
+				continue
+			}
+			pos := data.Position{X: x + c.origin.X, Y: y + c.origin.Y}
+			if isRevealed(pos) {
+				continue
+			}
+			best, bestPos, found = d, pos, true
+		}
+	}
+
+	if !found {
+		return Result{}, false
+	}
+
+	return c.toResult(bestPos), true
+}
+
+// FurthestKnown returns the reachable, already-revealed tile with the
+// highest cached distance - the edge of explored territory.
+func (c *Cache) FurthestKnown(isRevealed func(data.Position) bool) (Result, bool) {
+	var best uint16
+	var bestPos data.Position
+	found := false
+
+	for y := 0; y < c.height; y++ {
+		for x := 0; x < c.width; x++ {
+			d := c.dist[y*c.width+x]
+			if d == Unreachable || (found && d <= best) {
+				continue
+			}
+			pos := data.Position{X: x + c.origin.X, Y: y + c.origin.Y}
+			if !isRevealed(pos) {
+				continue
+			}
+			best, bestPos, found = d, pos, true
+		}
+	}
+
+	if !found {
+		return Result{}, false
+	}
+
+	return c.toResult(bestPos), true
+}
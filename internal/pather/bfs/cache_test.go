@@ -0,0 +1,131 @@
+package bfs
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+)
+
+// gridStub is a minimal pather.EscapeGrid backed by a [][]bool, true = walkable.
+type gridStub struct {
+	walkable [][]bool
+}
+
+func (g gridStub) IsWalkable(x, y int) bool {
+	if y < 0 || y >= len(g.walkable) || x < 0 || x >= len(g.walkable[y]) {
+		return false
+	}
+	return g.walkable[y][x]
+}
+
+func (g gridStub) Width() int {
+	if len(g.walkable) == 0 {
+		return 0
+	}
+	return len(g.walkable[0])
+}
+
+func (g gridStub) Height() int { return len(g.walkable) }
+
+func openGrid(width, height int) gridStub {
+	rows := make([][]bool, height)
+	for y := range rows {
+		row := make([]bool, width)
+		for x := range row {
+			row[x] = true
+		}
+		rows[y] = row
+	}
+	return gridStub{walkable: rows}
+}
+
+func TestCompute_DistancesAndParents(t *testing.T) {
+	grid := openGrid(5, 5)
+	origin := data.Position{X: 0, Y: 0}
+	source := data.Position{X: 2, Y: 2}
+
+	c := compute(grid, origin, area.BloodMoor, source)
+
+	if d := c.distanceAt(source); d != 0 {
+		t.Errorf("source distance = %d, want 0", d)
+	}
+	if d := c.distanceAt(data.Position{X: 3, Y: 2}); d != 1 {
+		t.Errorf("adjacent tile distance = %d, want 1", d)
+	}
+	if d := c.distanceAt(data.Position{X: 0, Y: 0}); d != 2 {
+		t.Errorf("diagonal-reachable corner distance = %d, want 2 (8-directional)", d)
+	}
+}
+
+func TestCompute_UnreachableBehindWall(t *testing.T) {
+	grid := openGrid(5, 5)
+	// Wall off column x=2 entirely, splitting the grid in two.
+	for y := 0; y < grid.Height(); y++ {
+		grid.walkable[y][2] = false
+	}
+
+	origin := data.Position{X: 0, Y: 0}
+	source := data.Position{X: 0, Y: 0}
+
+	c := compute(grid, origin, area.BloodMoor, source)
+
+	if d := c.distanceAt(data.Position{X: 4, Y: 4}); d != Unreachable {
+		t.Errorf("distance across the wall = %d, want Unreachable", d)
+	}
+}
+
+func TestCache_ClosestFoe(t *testing.T) {
+	grid := openGrid(10, 10)
+	origin := data.Position{X: 0, Y: 0}
+	source := data.Position{X: 0, Y: 0}
+
+	c := compute(grid, origin, area.BloodMoor, source)
+
+	monsters := data.Monsters{
+		{Position: data.Position{X: 5, Y: 5}},
+		{Position: data.Position{X: 1, Y: 0}},
+	}
+
+	res, found := c.ClosestFoe(monsters)
+	if !found {
+		t.Fatal("expected a reachable monster")
+	}
+	if res.Position != (data.Position{X: 1, Y: 0}) {
+		t.Errorf("closest monster = %+v, want {1 0}", res.Position)
+	}
+	if len(res.Path) == 0 || res.Path[0] != source {
+		t.Errorf("path should start at the BFS source, got %+v", res.Path)
+	}
+}
+
+func TestManager_ReusesCacheWithinDrift(t *testing.T) {
+	grid := openGrid(20, 20)
+	origin := data.Position{X: 0, Y: 0}
+
+	m := NewManager()
+	first := m.Get(grid, origin, area.BloodMoor, data.Position{X: 5, Y: 5})
+	second := m.Get(grid, origin, area.BloodMoor, data.Position{X: 6, Y: 5})
+
+	if first != second {
+		t.Error("a small move within MaxSourceDrift should reuse the cached BFS")
+	}
+
+	far := m.Get(grid, origin, area.BloodMoor, data.Position{X: 18, Y: 18})
+	if far == first {
+		t.Error("moving beyond MaxSourceDrift should trigger a recompute")
+	}
+}
+
+func TestManager_InvalidatesOnAreaChange(t *testing.T) {
+	grid := openGrid(10, 10)
+	origin := data.Position{X: 0, Y: 0}
+
+	m := NewManager()
+	first := m.Get(grid, origin, area.BloodMoor, data.Position{X: 1, Y: 1})
+	second := m.Get(grid, origin, area.ColdPlains, data.Position{X: 1, Y: 1})
+
+	if first == second {
+		t.Error("changing area should force a recompute even at the same position")
+	}
+}
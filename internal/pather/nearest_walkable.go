@@ -0,0 +1,53 @@
+package pather
+
+import "github.com/hectorgimenez/d2go/pkg/data"
+
+// DefaultNearestWalkableRadius is the radius MoveTo searches by default
+// before giving up and returning ErrNoPath. It's deliberately small: this
+// is meant to nudge off a single non-walkable cell (unit collision, a tile
+// boundary under an NPC/object/portal), not to relocate the destination.
+const DefaultNearestWalkableRadius = 8
+
+// NearestWalkable spirals outward from dest over the collision grid, layer
+// by layer, and returns the closest tile that is walkable and has a valid
+// path from the player (via hasPath). This handles the common case where a
+// quest NPC, object, or portal is reported at a coordinate that itself sits
+// on a non-walkable cell, which otherwise causes a spurious ErrNoPath.
+func NearestWalkable(grid EscapeGrid, dest data.Position, maxRadius int, hasPath func(data.Position) bool) (data.Position, bool) {
+	if grid.IsWalkable(dest.X, dest.Y) && hasPath(dest) {
+		return dest, true
+	}
+
+	for radius := 1; radius <= maxRadius; radius++ {
+		for _, candidate := range RingTiles(dest, radius) {
+			if grid.IsWalkable(candidate.X, candidate.Y) && hasPath(candidate) {
+				return candidate, true
+			}
+		}
+	}
+
+	return data.Position{}, false
+}
+
+// RingTiles returns the tiles forming the square ring at the given radius
+// around center, in a fixed (non-distance-sorted) order - good enough for
+// callers like NearestWalkable that return as soon as they find a valid
+// tile on the nearest ring that has one.
+func RingTiles(center data.Position, radius int) []data.Position {
+	tiles := make([]data.Position, 0, radius*8)
+
+	for dx := -radius; dx <= radius; dx++ {
+		tiles = append(tiles,
+			data.Position{X: center.X + dx, Y: center.Y - radius},
+			data.Position{X: center.X + dx, Y: center.Y + radius},
+		)
+	}
+	for dy := -radius + 1; dy <= radius-1; dy++ {
+		tiles = append(tiles,
+			data.Position{X: center.X - radius, Y: center.Y + dy},
+			data.Position{X: center.X + radius, Y: center.Y + dy},
+		)
+	}
+
+	return tiles
+}
@@ -0,0 +1,106 @@
+package pather
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+)
+
+// blockedGrid is an openGrid with a single non-walkable tile punched out.
+type blockedGrid struct {
+	openGrid
+	blocked data.Position
+}
+
+func (g blockedGrid) IsWalkable(x, y int) bool {
+	if x == g.blocked.X && y == g.blocked.Y {
+		return false
+	}
+	return g.openGrid.IsWalkable(x, y)
+}
+
+func TestNearestWalkable_DestAlreadyWalkable(t *testing.T) {
+	grid := openGrid{w: 20, h: 20}
+	dest := data.Position{X: 5, Y: 5}
+
+	pos, found := NearestWalkable(grid, dest, DefaultNearestWalkableRadius, func(data.Position) bool { return true })
+	if !found || pos != dest {
+		t.Fatalf("NearestWalkable = %+v, %v; want dest unchanged", pos, found)
+	}
+}
+
+func TestNearestWalkable_SpiralsOffBlockedTile(t *testing.T) {
+	dest := data.Position{X: 5, Y: 5}
+	grid := blockedGrid{openGrid: openGrid{w: 20, h: 20}, blocked: dest}
+
+	pos, found := NearestWalkable(grid, dest, DefaultNearestWalkableRadius, func(data.Position) bool { return true })
+	if !found {
+		t.Fatal("expected a nearby walkable tile")
+	}
+	if pos == dest {
+		t.Error("dest itself is non-walkable, should not be returned")
+	}
+	if !grid.IsWalkable(pos.X, pos.Y) {
+		t.Errorf("returned tile %+v is not walkable", pos)
+	}
+}
+
+func TestNearestWalkable_RespectsHasPath(t *testing.T) {
+	dest := data.Position{X: 5, Y: 5}
+	grid := blockedGrid{openGrid: openGrid{w: 20, h: 20}, blocked: dest}
+
+	// Only accept a tile two rings out, to confirm hasPath gates candidates
+	// on rings closer in even when they're walkable.
+	want := data.Position{X: 7, Y: 5}
+	hasPath := func(pos data.Position) bool { return pos == want }
+
+	pos, found := NearestWalkable(grid, dest, DefaultNearestWalkableRadius, hasPath)
+	if !found || pos != want {
+		t.Fatalf("NearestWalkable = %+v, %v; want %+v, true", pos, found, want)
+	}
+}
+
+func TestNearestWalkable_GivesUpBeyondMaxRadius(t *testing.T) {
+	dest := data.Position{X: 5, Y: 5}
+	grid := openGrid{w: 20, h: 20}
+
+	// Only tiles outside the radius-3 search box have a path.
+	hasPath := func(pos data.Position) bool { return abs(pos.X-dest.X) > 3 || abs(pos.Y-dest.Y) > 3 }
+
+	_, found := NearestWalkable(grid, dest, 3, hasPath)
+	if found {
+		t.Error("expected no candidate when only tiles beyond maxRadius have a path")
+	}
+}
+
+func TestRingTiles_CountAndDistinctness(t *testing.T) {
+	center := data.Position{X: 10, Y: 10}
+
+	for _, radius := range []int{1, 2, 3} {
+		tiles := RingTiles(center, radius)
+		want := 8 * radius
+		if len(tiles) != want {
+			t.Errorf("radius %d: len(tiles) = %d, want %d", radius, len(tiles), want)
+		}
+
+		seen := make(map[data.Position]bool, len(tiles))
+		for _, tile := range tiles {
+			if seen[tile] {
+				t.Errorf("radius %d: duplicate tile %+v", radius, tile)
+			}
+			seen[tile] = true
+
+			dx, dy := tile.X-center.X, tile.Y-center.Y
+			if abs(dx) != radius && abs(dy) != radius {
+				t.Errorf("radius %d: tile %+v is not on the ring", radius, tile)
+			}
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,231 @@
+package pather
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+)
+
+// MaxVertexEscapeTiles bounds how far the vertex escape planner is willing to
+// search for a way out. It's a short-range planner by design: if an escape
+// corridor this size doesn't exist, the situation isn't a "locked-in" corner
+// case anymore and the long-range pathfinder's ErrNoPath should stand.
+const MaxVertexEscapeTiles = 40
+
+// ObstaclePolygon is the outer-vertex approximation of a single impassable
+// cluster (a body pile, a door frame, a cracked wall chunk, ...). We don't
+// need the real polygon, just enough corners to route a visibility graph
+// around it.
+type ObstaclePolygon struct {
+	Vertices []data.Position
+}
+
+// NewBoxObstacle approximates an impassable cluster centered on center as a
+// square of the given radius and returns its four corners. It's a cheap
+// stand-in for tracing the cluster's real outline, good enough for the
+// visibility graph since FindVertexEscape only needs corners to route
+// around, not an exact silhouette.
+func NewBoxObstacle(center data.Position, radius int) ObstaclePolygon {
+	return ObstaclePolygon{
+		Vertices: []data.Position{
+			{X: center.X - radius, Y: center.Y - radius},
+			{X: center.X + radius, Y: center.Y - radius},
+			{X: center.X + radius, Y: center.Y + radius},
+			{X: center.X - radius, Y: center.Y + radius},
+		},
+	}
+}
+
+// vertexGraph is the small graph built from the player's position, the
+// destination, and the walkable outer vertices of every obstacle polygon
+// handed to FindVertexEscape. Edges only exist between vertices that
+// have a clear line of sight over the collision grid.
+type vertexGraph struct {
+	nodes []data.Position
+	edges map[int][]edge
+}
+
+type edge struct {
+	to   int
+	cost float64
+}
+
+// EscapeGrid is the minimal view of the collision grid the escape planner
+// needs. step.MoveTo satisfies it with an adapter around the collision grid
+// of ctx.Data.AreaData.Grid.
+type EscapeGrid interface {
+	// IsWalkable reports whether the given tile, in the same coordinates
+	// as the positions passed alongside the grid, can be occupied by the
+	// player.
+	IsWalkable(x, y int) bool
+	Width() int
+	Height() int
+}
+
+// FindVertexEscape searches for a short escape corridor out of a
+// "locked-in" spot, where the long-range tile pathfinder reports no path
+// (ErrNoPath) or keeps bouncing off the same obstacle
+// (maxObstacleBypassAttempts exhausted in step.MoveTo).
+//
+// obstacles are the nearby impassable clusters (bodies, gore, chests, walls)
+// treated as polygons; their outer vertices become graph nodes alongside
+// from and dest. isLongRangeValid reports whether the long-range pathfinder
+// can path from a given tile to the final destination - once the escape
+// reaches such a tile, FindVertexEscape stops and returns the corridor so
+// the caller can splice it onto the long-range path.
+//
+// It returns the escape corridor (in the same coordinates as from and dest,
+// from excluded) and whether one was found within MaxVertexEscapeTiles.
+func FindVertexEscape(grid EscapeGrid, from, dest data.Position, obstacles []ObstaclePolygon, isLongRangeValid func(data.Position) bool) ([]data.Position, bool) {
+	graph := buildVertexGraph(grid, from, dest, obstacles)
+	if len(graph.nodes) == 0 {
+		return nil, false
+	}
+
+	path, found := graph.aStar(0, isLongRangeValid)
+	if !found || len(path) == 0 {
+		return nil, false
+	}
+	// Measure the corridor in tiles walked, not in graph hops.
+	corridorTiles := 0.0
+	for i := 1; i < len(path); i++ {
+		corridorTiles += euclideanDistance(path[i-1], path[i])
+	}
+	if corridorTiles > MaxVertexEscapeTiles {
+		return nil, false
+	}
+
+	// Drop the starting node, the caller already stands there.
+	return path[1:], true
+}
+
+func buildVertexGraph(grid EscapeGrid, from, dest data.Position, obstacles []ObstaclePolygon) *vertexGraph {
+	g := &vertexGraph{edges: map[int][]edge{}}
+	g.nodes = append(g.nodes, from, dest)
+
+	for _, obstacle := range obstacles {
+		for _, v := range obstacle.Vertices {
+			if grid.IsWalkable(v.X, v.Y) {
+				g.nodes = append(g.nodes, v)
+			}
+		}
+	}
+
+	for i := range g.nodes {
+		for j := i + 1; j < len(g.nodes); j++ {
+			if hasLineOfSight(grid, g.nodes[i], g.nodes[j]) {
+				dist := euclideanDistance(g.nodes[i], g.nodes[j])
+				g.edges[i] = append(g.edges[i], edge{to: j, cost: dist})
+				g.edges[j] = append(g.edges[j], edge{to: i, cost: dist})
+			}
+		}
+	}
+
+	return g
+}
+
+// hasLineOfSight walks the straight line between a and b over the collision
+// grid using a Bresenham-style stepper, stopping as soon as a non-walkable
+// tile is crossed.
+func hasLineOfSight(grid EscapeGrid, a, b data.Position) bool {
+	dx := b.X - a.X
+	dy := b.Y - a.Y
+	steps := int(math.Max(math.Abs(float64(dx)), math.Abs(float64(dy))))
+	if steps == 0 {
+		return true
+	}
+
+	xInc := float64(dx) / float64(steps)
+	yInc := float64(dy) / float64(steps)
+	x, y := float64(a.X), float64(a.Y)
+
+	for i := 0; i <= steps; i++ {
+		if !grid.IsWalkable(int(math.Round(x)), int(math.Round(y))) {
+			return false
+		}
+		x += xInc
+		y += yInc
+	}
+
+	return true
+}
+
+func euclideanDistance(a, b data.Position) float64 {
+	dx := float64(a.X - b.X)
+	dy := float64(a.Y - b.Y)
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// aStar runs A* from startIdx over the vertex graph. Any node from which
+// isLongRangeValid reports true is treated as a goal, since reaching it
+// means the long-range path becomes usable again.
+func (g *vertexGraph) aStar(startIdx int, isLongRangeValid func(data.Position) bool) ([]data.Position, bool) {
+	open := &vertexHeap{}
+	heap.Init(open)
+	heap.Push(open, &vertexHeapItem{index: startIdx, priority: 0})
+
+	cameFrom := map[int]int{}
+	gScore := map[int]float64{startIdx: 0}
+	visited := map[int]bool{}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*vertexHeapItem)
+		if visited[current.index] {
+			continue
+		}
+		visited[current.index] = true
+
+		if current.index != startIdx && isLongRangeValid(g.nodes[current.index]) {
+			return g.reconstructPath(cameFrom, current.index), true
+		}
+
+		for _, e := range g.edges[current.index] {
+			tentative := gScore[current.index] + e.cost
+			if existing, ok := gScore[e.to]; !ok || tentative < existing {
+				gScore[e.to] = tentative
+				cameFrom[e.to] = current.index
+				heap.Push(open, &vertexHeapItem{index: e.to, priority: tentative})
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func (g *vertexGraph) reconstructPath(cameFrom map[int]int, goalIdx int) []data.Position {
+	indices := []int{goalIdx}
+	for {
+		prev, ok := cameFrom[indices[len(indices)-1]]
+		if !ok {
+			break
+		}
+		indices = append(indices, prev)
+	}
+
+	path := make([]data.Position, len(indices))
+	for i, idx := range indices {
+		path[len(indices)-1-i] = g.nodes[idx]
+	}
+
+	return path
+}
+
+type vertexHeapItem struct {
+	index    int
+	priority float64
+}
+
+type vertexHeap []*vertexHeapItem
+
+func (h vertexHeap) Len() int            { return len(h) }
+func (h vertexHeap) Less(i, j int) bool  { return h[i].priority < h[j].priority }
+func (h vertexHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *vertexHeap) Push(x interface{}) { *h = append(*h, x.(*vertexHeapItem)) }
+func (h *vertexHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,98 @@
+package pather
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+)
+
+// openGrid is a w x h grid with every tile walkable.
+type openGrid struct{ w, h int }
+
+func (g openGrid) IsWalkable(x, y int) bool { return x >= 0 && x < g.w && y >= 0 && y < g.h }
+func (g openGrid) Width() int               { return g.w }
+func (g openGrid) Height() int              { return g.h }
+
+func TestFindVertexEscape_NoObstaclesGoesStraightToGoal(t *testing.T) {
+	grid := openGrid{w: 20, h: 20}
+	from := data.Position{X: 0, Y: 0}
+	dest := data.Position{X: 10, Y: 0}
+
+	isLongRangeValid := func(pos data.Position) bool { return pos == dest }
+
+	path, found := FindVertexEscape(grid, from, dest, nil, isLongRangeValid)
+	if !found {
+		t.Fatal("expected an escape corridor with direct line of sight to dest")
+	}
+	if len(path) != 1 || path[0] != dest {
+		t.Errorf("path = %+v, want a single hop straight to dest", path)
+	}
+}
+
+func TestFindVertexEscape_RoutesAroundObstacle(t *testing.T) {
+	from := data.Position{X: 0, Y: 5}
+	dest := data.Position{X: 10, Y: 5}
+
+	// A non-walkable tile directly between from and dest blocks line of
+	// sight over the grid, and the box obstacle around it gives the planner
+	// corners to route via.
+	grid := blockedGrid{openGrid: openGrid{w: 20, h: 20}, blocked: data.Position{X: 5, Y: 5}}
+	obstacles := []ObstaclePolygon{NewBoxObstacle(data.Position{X: 5, Y: 5}, 2)}
+
+	isLongRangeValid := func(pos data.Position) bool { return pos == dest }
+
+	path, found := FindVertexEscape(grid, from, dest, obstacles, isLongRangeValid)
+	if !found {
+		t.Fatal("expected an escape corridor routed around the obstacle")
+	}
+	if path[len(path)-1] != dest {
+		t.Errorf("path should end at dest, got %+v", path)
+	}
+	if len(path) < 2 {
+		t.Errorf("expected at least one intermediate vertex, got path %+v", path)
+	}
+}
+
+func TestFindVertexEscape_NoPathWhenGoalUnreachable(t *testing.T) {
+	grid := openGrid{w: 20, h: 20}
+	from := data.Position{X: 0, Y: 0}
+	dest := data.Position{X: 10, Y: 0}
+
+	isLongRangeValid := func(data.Position) bool { return false }
+
+	_, found := FindVertexEscape(grid, from, dest, nil, isLongRangeValid)
+	if found {
+		t.Error("expected no escape when isLongRangeValid never accepts a node")
+	}
+}
+
+func TestFindVertexEscape_RespectsMaxVertexEscapeTiles(t *testing.T) {
+	grid := openGrid{w: 200, h: 200}
+	from := data.Position{X: 0, Y: 0}
+	dest := data.Position{X: 150, Y: 0}
+
+	isLongRangeValid := func(pos data.Position) bool { return pos == dest }
+
+	_, found := FindVertexEscape(grid, from, dest, nil, isLongRangeValid)
+	if found {
+		t.Error("escape corridor longer than MaxVertexEscapeTiles should be rejected")
+	}
+}
+
+func TestNewBoxObstacle_HasFourCorners(t *testing.T) {
+	obstacle := NewBoxObstacle(data.Position{X: 5, Y: 5}, 2)
+	if len(obstacle.Vertices) != 4 {
+		t.Fatalf("len(Vertices) = %d, want 4", len(obstacle.Vertices))
+	}
+	want := map[data.Position]bool{
+		{X: 3, Y: 3}: true,
+		{X: 7, Y: 3}: true,
+		{X: 7, Y: 7}: true,
+		{X: 3, Y: 7}: true,
+	}
+	for _, v := range obstacle.Vertices {
+		if !want[v] {
+			t.Errorf("unexpected corner %+v", v)
+		}
+	}
+}